@@ -0,0 +1,164 @@
+// Package circuitbreaker implements a classic three-state (closed / open /
+// half-open) circuit breaker for protecting callers against a downstream
+// dependency that is failing repeatedly.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is one of Closed, Open, or HalfOpen.
+type State int
+
+const (
+	// Closed is the normal state: every call is allowed through.
+	Closed State = iota
+	// Open rejects every call until the cool-down window elapses.
+	Open
+	// HalfOpen allows a limited number of trial calls through to decide
+	// whether to return to Closed or back to Open.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrOpen is returned by Call (and surfaced by callers of Allow) when the
+// breaker is open and the call was rejected without running.
+var ErrOpen = errors.New("circuitbreaker: breaker is open")
+
+// Breaker is a three-state circuit breaker. The zero value is not usable;
+// construct one with New.
+type Breaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	cooldown         time.Duration
+	halfOpenTrials   int
+
+	state            State
+	consecutiveFails int
+	openedAt         time.Time
+	halfOpenAttempts int
+}
+
+// New builds a Breaker that opens after failureThreshold consecutive
+// failures, stays open for cooldown, then allows up to halfOpenTrials trial
+// calls through. The first trial to succeed closes the breaker again; a
+// failed trial reopens it.
+func New(failureThreshold int, cooldown time.Duration, halfOpenTrials int) *Breaker {
+	return &Breaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		halfOpenTrials:   halfOpenTrials,
+		state:            Closed,
+	}
+}
+
+// State reports the breaker's current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a call may proceed right now, advancing Open to
+// HalfOpen once the cool-down window has elapsed.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Closed:
+		return true
+	case Open:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = HalfOpen
+		b.halfOpenAttempts = 0
+		return b.allowHalfOpenLocked()
+	case HalfOpen:
+		return b.allowHalfOpenLocked()
+	default:
+		return false
+	}
+}
+
+func (b *Breaker) allowHalfOpenLocked() bool {
+	if b.halfOpenAttempts >= b.halfOpenTrials {
+		return false
+	}
+	b.halfOpenAttempts++
+	return true
+}
+
+// RecordSuccess reports that an allowed call succeeded.
+func (b *Breaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFails = 0
+	if b.state != Closed {
+		b.state = Closed
+		b.halfOpenAttempts = 0
+	}
+}
+
+// RecordFailure reports that an allowed call failed, opening the breaker if
+// the failure threshold is reached (or immediately, if the failing call was
+// itself a half-open trial).
+func (b *Breaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == HalfOpen {
+		b.open()
+		return
+	}
+
+	b.consecutiveFails++
+	if b.consecutiveFails >= b.failureThreshold {
+		b.open()
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = Open
+	b.openedAt = time.Now()
+	b.consecutiveFails = 0
+	b.halfOpenAttempts = 0
+}
+
+// Record is a convenience for RecordSuccess/RecordFailure based on whether
+// err is nil.
+func (b *Breaker) Record(err error) {
+	if err != nil {
+		b.RecordFailure()
+		return
+	}
+	b.RecordSuccess()
+}
+
+// Call runs fn if the breaker allows it, recording the outcome. It returns
+// ErrOpen without running fn if the breaker is open.
+func (b *Breaker) Call(fn func() error) error {
+	if !b.Allow() {
+		return ErrOpen
+	}
+	err := fn()
+	b.Record(err)
+	return err
+}
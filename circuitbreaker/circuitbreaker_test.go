@@ -0,0 +1,98 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := New(3, time.Minute, 1)
+
+	for i := 0; i < 2; i++ {
+		if err := b.Call(func() error { return errBoom }); err != errBoom {
+			t.Fatalf("expected errBoom, got %v", err)
+		}
+	}
+	if b.State() != Closed {
+		t.Fatalf("expected Closed before threshold, got %v", b.State())
+	}
+
+	if err := b.Call(func() error { return errBoom }); err != errBoom {
+		t.Fatalf("expected errBoom, got %v", err)
+	}
+	if b.State() != Open {
+		t.Fatalf("expected Open after threshold, got %v", b.State())
+	}
+
+	if err := b.Call(func() error { return nil }); err != ErrOpen {
+		t.Fatalf("expected ErrOpen while breaker is open, got %v", err)
+	}
+}
+
+func TestBreakerHalfOpenAfterCooldown(t *testing.T) {
+	b := New(1, 10*time.Millisecond, 1)
+
+	if err := b.Call(func() error { return errBoom }); err != errBoom {
+		t.Fatalf("expected errBoom, got %v", err)
+	}
+	if b.State() != Open {
+		t.Fatalf("expected Open, got %v", b.State())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatalf("expected a half-open trial to be allowed after cooldown")
+	}
+	if b.State() != HalfOpen {
+		t.Fatalf("expected HalfOpen, got %v", b.State())
+	}
+}
+
+func TestBreakerClosesOnSuccessfulHalfOpenTrial(t *testing.T) {
+	b := New(1, 10*time.Millisecond, 1)
+
+	b.Call(func() error { return errBoom })
+	time.Sleep(20 * time.Millisecond)
+
+	if err := b.Call(func() error { return nil }); err != nil {
+		t.Fatalf("expected trial to succeed, got %v", err)
+	}
+	if b.State() != Closed {
+		t.Fatalf("expected Closed after a successful trial, got %v", b.State())
+	}
+}
+
+func TestBreakerReopensOnFailedHalfOpenTrial(t *testing.T) {
+	b := New(1, 10*time.Millisecond, 1)
+
+	b.Call(func() error { return errBoom })
+	time.Sleep(20 * time.Millisecond)
+
+	if err := b.Call(func() error { return errBoom }); err != errBoom {
+		t.Fatalf("expected errBoom from the trial, got %v", err)
+	}
+	if b.State() != Open {
+		t.Fatalf("expected Open after a failed trial, got %v", b.State())
+	}
+}
+
+func TestBreakerLimitsHalfOpenTrials(t *testing.T) {
+	b := New(1, 10*time.Millisecond, 2)
+
+	b.Call(func() error { return errBoom })
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatalf("expected first trial to be allowed")
+	}
+	if !b.Allow() {
+		t.Fatalf("expected second trial to be allowed")
+	}
+	if b.Allow() {
+		t.Fatalf("expected a third concurrent trial to be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Lilsofie/cc-ss24-exercise-1/circuitbreaker"
+)
+
+// MongoCollection is the subset of *mongo.Collection the book API calls.
+// It exists so CBCollection can wrap the real collection, and tests can
+// substitute a fake one, without either depending on mongo-driver internals.
+type MongoCollection interface {
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+}
+
+// CBCollection wraps a MongoCollection with a circuit breaker, so repeated
+// Mongo failures short-circuit future calls instead of letting clients hang
+// on a downstream dependency that is already failing.
+type CBCollection struct {
+	coll MongoCollection
+	cb   *circuitbreaker.Breaker
+}
+
+// NewCBCollection wraps coll with cb.
+func NewCBCollection(coll MongoCollection, cb *circuitbreaker.Breaker) *CBCollection {
+	return &CBCollection{coll: coll, cb: cb}
+}
+
+func (c *CBCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if !c.cb.Allow() {
+		return 0, circuitbreaker.ErrOpen
+	}
+	count, err := c.coll.CountDocuments(ctx, filter, opts...)
+	c.cb.Record(err)
+	return count, err
+}
+
+func (c *CBCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if !c.cb.Allow() {
+		return nil, circuitbreaker.ErrOpen
+	}
+	res, err := c.coll.InsertOne(ctx, document, opts...)
+	c.cb.Record(err)
+	return res, err
+}
+
+func (c *CBCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if !c.cb.Allow() {
+		return nil, circuitbreaker.ErrOpen
+	}
+	res, err := c.coll.UpdateOne(ctx, filter, update, opts...)
+	c.cb.Record(err)
+	return res, err
+}
+
+func (c *CBCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if !c.cb.Allow() {
+		return nil, circuitbreaker.ErrOpen
+	}
+	res, err := c.coll.DeleteOne(ctx, filter, opts...)
+	c.cb.Record(err)
+	return res, err
+}
+
+func (c *CBCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if !c.cb.Allow() {
+		return nil, circuitbreaker.ErrOpen
+	}
+	cursor, err := c.coll.Find(ctx, filter, opts...)
+	c.cb.Record(err)
+	return cursor, err
+}
+
+func (c *CBCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if !c.cb.Allow() {
+		return mongo.NewSingleResultFromDocument(bson.D{}, circuitbreaker.ErrOpen, nil)
+	}
+	res := c.coll.FindOne(ctx, filter, opts...)
+	// mongo.ErrNoDocuments just means no match, not a Mongo failure — don't
+	// let "book not found" lookups trip the breaker.
+	if err := res.Err(); errors.Is(err, mongo.ErrNoDocuments) {
+		c.cb.Record(nil)
+	} else {
+		c.cb.Record(err)
+	}
+	return res
+}
+
+func (c *CBCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if !c.cb.Allow() {
+		return nil, circuitbreaker.ErrOpen
+	}
+	cursor, err := c.coll.Aggregate(ctx, pipeline, opts...)
+	c.cb.Record(err)
+	return cursor, err
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Lilsofie/cc-ss24-exercise-1/circuitbreaker"
+)
+
+// fakeMongoCollection lets tests make every call fail (or succeed) without
+// a real MongoDB connection.
+type fakeMongoCollection struct {
+	failCount int64
+	// notFound makes FindOne always report mongo.ErrNoDocuments instead of
+	// consulting failCount.
+	notFound bool
+}
+
+var errFakeMongoDown = errors.New("fake: mongo unavailable")
+
+func (f *fakeMongoCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return 0, f.nextErr()
+}
+
+func (f *fakeMongoCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	return nil, f.nextErr()
+}
+
+func (f *fakeMongoCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return nil, f.nextErr()
+}
+
+func (f *fakeMongoCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return nil, f.nextErr()
+}
+
+func (f *fakeMongoCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return nil, f.nextErr()
+}
+
+func (f *fakeMongoCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if f.notFound {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(bson.D{}, f.nextErr(), nil)
+}
+
+func (f *fakeMongoCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return nil, f.nextErr()
+}
+
+func (f *fakeMongoCollection) nextErr() error {
+	if f.failCount > 0 {
+		f.failCount--
+		return errFakeMongoDown
+	}
+	return nil
+}
+
+func TestCBCollectionTripsAfterRepeatedFailures(t *testing.T) {
+	fake := &fakeMongoCollection{failCount: 10}
+	cb := circuitbreaker.New(3, time.Minute, 1)
+	coll := NewCBCollection(fake, cb)
+
+	for i := 0; i < 3; i++ {
+		if _, err := coll.CountDocuments(context.Background(), nil); !errors.Is(err, errFakeMongoDown) {
+			t.Fatalf("expected underlying error, got %v", err)
+		}
+	}
+
+	if _, err := coll.CountDocuments(context.Background(), nil); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("expected circuitbreaker.ErrOpen once tripped, got %v", err)
+	}
+}
+
+func TestCBCollectionResetsAfterCooldown(t *testing.T) {
+	fake := &fakeMongoCollection{failCount: 1}
+	cb := circuitbreaker.New(1, 10*time.Millisecond, 1)
+	coll := NewCBCollection(fake, cb)
+
+	if _, err := coll.CountDocuments(context.Background(), nil); !errors.Is(err, errFakeMongoDown) {
+		t.Fatalf("expected underlying error, got %v", err)
+	}
+	if _, err := coll.CountDocuments(context.Background(), nil); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("expected circuitbreaker.ErrOpen while open, got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := coll.CountDocuments(context.Background(), nil); err != nil {
+		t.Fatalf("expected the half-open trial to succeed and close the breaker, got %v", err)
+	}
+	if cb.State() != circuitbreaker.Closed {
+		t.Fatalf("expected Closed after a successful trial, got %v", cb.State())
+	}
+}
+
+func TestCBCollectionFindOneIgnoresNotFound(t *testing.T) {
+	fake := &fakeMongoCollection{notFound: true}
+	cb := circuitbreaker.New(3, time.Minute, 1)
+	coll := NewCBCollection(fake, cb)
+
+	for i := 0; i < 10; i++ {
+		res := coll.FindOne(context.Background(), nil)
+		if !errors.Is(res.Err(), mongo.ErrNoDocuments) {
+			t.Fatalf("expected mongo.ErrNoDocuments, got %v", res.Err())
+		}
+	}
+
+	if cb.State() != circuitbreaker.Closed {
+		t.Fatalf("expected repeated not-found lookups to leave the breaker Closed, got %v", cb.State())
+	}
+}
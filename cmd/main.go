@@ -0,0 +1,695 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Lilsofie/cc-ss24-exercise-1/circuitbreaker"
+	"github.com/Lilsofie/cc-ss24-exercise-1/enrich"
+	"github.com/Lilsofie/cc-ss24-exercise-1/errs"
+	"github.com/Lilsofie/cc-ss24-exercise-1/events"
+	"github.com/Lilsofie/cc-ss24-exercise-1/metrics"
+)
+
+// writeJSONError writes a consistent machine-readable error envelope so
+// API clients never have to pattern-match on plain-text bodies.
+func writeJSONError(c echo.Context, code int, msg string) error {
+	return c.JSON(code, map[string]string{
+		"status": "error",
+		"reason": msg,
+	})
+}
+
+// httpErrorHandler is registered as the Echo instance's error handler so
+// every sentinel error from the errs package reaches the client as the same
+// JSON envelope writeJSONError produces, regardless of which handler
+// returned it. Echo's own *echo.HTTPError (e.g. an unknown route) keeps its
+// status code.
+func httpErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	code, msg := errs.StatusCode(err), err.Error()
+	if he, ok := err.(*echo.HTTPError); ok {
+		code, msg = he.Code, fmt.Sprint(he.Message)
+	}
+	if writeErr := writeJSONError(c, code, msg); writeErr != nil {
+		c.Logger().Error(writeErr)
+	}
+}
+
+// dbErrorResponse writes the JSON error envelope for a failed database
+// call, distinguishing a circuit breaker rejection (503) from any other
+// Mongo error (500, with msg).
+func dbErrorResponse(c echo.Context, err error, msg string) error {
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		return writeJSONError(c, http.StatusServiceUnavailable, "Service temporarily unavailable")
+	}
+	return writeJSONError(c, http.StatusInternalServerError, msg)
+}
+
+// BookStore is the MongoDB document shape for a single book.
+type BookStore struct {
+	ID          string `json:"ID" bson:"ID"`
+	BookName    string `json:"BookName" bson:"BookName"`
+	BookAuthor  string `json:"BookAuthor" bson:"BookAuthor"`
+	BookEdition string `json:"BookEdition" bson:"BookEdition"`
+	BookPages   string `json:"BookPages" bson:"BookPages"`
+	BookYear    string `json:"BookYear" bson:"BookYear"`
+
+	// Populated by the enrich subsystem from external metadata providers.
+	ISBN        string `json:"ISBN,omitempty" bson:"ISBN,omitempty"`
+	CoverURL    string `json:"CoverURL,omitempty" bson:"CoverURL,omitempty"`
+	Description string `json:"Description,omitempty" bson:"Description,omitempty"`
+}
+
+// needsEnrichment reports whether any of the metadata fields populated by
+// the enrich subsystem are still missing.
+func (b BookStore) needsEnrichment() bool {
+	return b.ISBN == "" || b.CoverURL == "" || b.Description == ""
+}
+
+// Template wraps html/template so it satisfies echo.Renderer.
+type Template struct {
+	templates *template.Template
+}
+
+func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	return t.templates.ExecuteTemplate(w, name, data)
+}
+
+// loadTemplates parses the html templates used to render the book list
+// pages. Tests set SKIP_TEMPLATES=true since the working directory they run
+// from has no templates folder on disk.
+func loadTemplates() *Template {
+	if os.Getenv("SKIP_TEMPLATES") == "true" {
+		return nil
+	}
+	return &Template{
+		templates: template.Must(template.ParseGlob("templates/*.html")),
+	}
+}
+
+// prepareDatabase returns the collection used to store books, creating it
+// (and a unique index on ID) if it does not already exist.
+func prepareDatabase(client *mongo.Client, dbName string, collectionName string) (*mongo.Collection, error) {
+	db := client.Database(dbName)
+	coll := db.Collection(collectionName)
+
+	_, err := coll.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "ID", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Supports the case-insensitive/sorted lookups searchBooks runs.
+	_, err = coll.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "BookName", Value: 1}}},
+		{Keys: bson.D{{Key: "BookAuthor", Value: 1}}},
+		{Keys: bson.D{{Key: "BookYear", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return coll, nil
+}
+
+// prepareData seeds the collection with a handful of classics the first
+// time it is empty, so the UI has something to show on a fresh database.
+func prepareData(client *mongo.Client, coll *mongo.Collection) {
+	count, err := coll.CountDocuments(context.TODO(), bson.M{})
+	if err != nil {
+		log.Printf("prepareData: failed to count documents: %v", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	seed := []interface{}{
+		BookStore{ID: "example1", BookName: "1984", BookAuthor: "George Orwell", BookEdition: "1st", BookPages: "328", BookYear: "1949"},
+		BookStore{ID: "example2", BookName: "Brave New World", BookAuthor: "Aldous Huxley", BookEdition: "1st", BookPages: "311", BookYear: "1932"},
+		BookStore{ID: "example3", BookName: "Fahrenheit 451", BookAuthor: "Ray Bradbury", BookEdition: "1st", BookPages: "256", BookYear: "1953"},
+	}
+
+	if _, err := coll.InsertMany(context.TODO(), seed); err != nil {
+		log.Printf("prepareData: failed to seed collection: %v", err)
+	}
+}
+
+// findAllBooks returns every book in the collection, shaped for the
+// front-end templates/JSON consumers (lower-cased field names).
+func findAllBooks(coll MongoCollection) []map[string]interface{} {
+	cursor, err := coll.Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []map[string]interface{}
+	for cursor.Next(context.TODO()) {
+		var book BookStore
+		if err := cursor.Decode(&book); err != nil {
+			continue
+		}
+		results = append(results, bookToMap(book))
+	}
+	return results
+}
+
+func bookToMap(book BookStore) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          book.ID,
+		"title":       book.BookName,
+		"author":      book.BookAuthor,
+		"edition":     book.BookEdition,
+		"pages":       book.BookPages,
+		"year":        book.BookYear,
+		"isbn":        book.ISBN,
+		"cover":       book.CoverURL,
+		"description": book.Description,
+	}
+}
+
+// findAuthors groups every book by author, returning the author name
+// alongside the titles they wrote.
+func findAuthors(coll MongoCollection) []map[string]interface{} {
+	return groupBooksBy(coll, "BookAuthor", "author")
+}
+
+// findYears groups every book by publication year, returning the year
+// alongside the titles published in it.
+func findYears(coll MongoCollection) []map[string]interface{} {
+	return groupBooksBy(coll, "BookYear", "year")
+}
+
+// groupBooksBy runs a $group aggregation over the given book field and
+// returns one entry per distinct value, with "books" holding the titles.
+func groupBooksBy(coll MongoCollection, field string, label string) []map[string]interface{} {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + field},
+			{Key: "books", Value: bson.D{{Key: "$push", Value: "$BookName"}}},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []map[string]interface{}
+	for cursor.Next(context.TODO()) {
+		var row struct {
+			ID    string   `bson:"_id"`
+			Books []string `bson:"books"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			continue
+		}
+		books := make([]interface{}, len(row.Books))
+		for i, b := range row.Books {
+			books[i] = b
+		}
+		results = append(results, map[string]interface{}{
+			label:   row.ID,
+			"books": books,
+		})
+	}
+	return results
+}
+
+// SearchOptions configures searchBooks.
+type SearchOptions struct {
+	Query  string // matched case-insensitively against BookName or BookAuthor
+	Author string // matched case-insensitively against BookAuthor
+	Year   string // exact match against BookYear
+	Page   int
+	Limit  int
+	Sort   string // "name", "author", or "year"; prefix with "-" to reverse
+}
+
+// searchField maps a SearchOptions.Sort value to the BookStore field it
+// sorts on. Unknown values fall back to BookName.
+func searchField(sort string) string {
+	switch strings.TrimPrefix(sort, "-") {
+	case "author":
+		return "BookAuthor"
+	case "year":
+		return "BookYear"
+	default:
+		return "BookName"
+	}
+}
+
+// searchBooks runs a paginated, case-insensitive search over the book
+// collection, returning the matching page alongside the total match count.
+func searchBooks(coll MongoCollection, opts SearchOptions) (items []map[string]interface{}, total int64, err error) {
+	filter := bson.M{}
+	if opts.Query != "" {
+		filter["$or"] = []bson.M{
+			{"BookName": bson.M{"$regex": opts.Query, "$options": "i"}},
+			{"BookAuthor": bson.M{"$regex": opts.Query, "$options": "i"}},
+		}
+	}
+	if opts.Author != "" {
+		filter["BookAuthor"] = bson.M{"$regex": opts.Author, "$options": "i"}
+	}
+	if opts.Year != "" {
+		filter["BookYear"] = opts.Year
+	}
+
+	total, err = coll.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := opts.Limit
+	if limit < 1 {
+		limit = 20
+	}
+
+	sortDir := 1
+	if strings.HasPrefix(opts.Sort, "-") {
+		sortDir = -1
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: searchField(opts.Sort), Value: sortDir}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := coll.Find(context.TODO(), filter, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	items = []map[string]interface{}{}
+	for cursor.Next(context.TODO()) {
+		var book BookStore
+		if err := cursor.Decode(&book); err != nil {
+			continue
+		}
+		items = append(items, bookToMap(book))
+	}
+
+	return items, total, nil
+}
+
+// atoiOrDefault parses s as a positive int, falling back to def if s is
+// empty or not a valid number.
+func atoiOrDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
+
+// recordEventAsync records a book event in the background so the request
+// that triggered it does not wait on the audit log write. ts should be
+// captured by the caller before spawning the goroutine, so events recorded
+// from independent goroutines still land in request order rather than
+// whatever order the scheduler happens to run them in.
+func recordEventAsync(rec *events.Recorder, bookID string, eventType events.Type, payload interface{}, ts time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := rec.RecordAt(ctx, bookID, eventType, payload, ts); err != nil {
+		log.Printf("recordEventAsync: failed to record %s event for book %s: %v", eventType, bookID, err)
+	}
+}
+
+// timedDBOp runs op, observing its latency under the given operation name.
+func timedDBOp(m metrics.Metrics, operation string, op func()) {
+	start := time.Now()
+	op()
+	m.ObserveDBOperation(operation, time.Since(start))
+}
+
+// registerRoutes wires every HTTP route onto e, backed by coll, recording
+// DB-timing observations on m and book events on rec as it goes. Metadata
+// lookups (background enrichment and POST /api/books/:id/enrich) query
+// providers, so tests can pass fakes instead of hitting Google Books / Open
+// Library over the network.
+func registerRoutes(e *echo.Echo, coll MongoCollection, m metrics.Metrics, rec *events.Recorder, providers []enrich.MetadataProvider) {
+	e.GET("/", func(c echo.Context) error {
+		var books []map[string]interface{}
+		timedDBOp(m, "find_all_books", func() { books = findAllBooks(coll) })
+		return c.Render(http.StatusOK, "index.html", books)
+	})
+
+	e.GET("/api/books", func(c echo.Context) error {
+		var books []map[string]interface{}
+		timedDBOp(m, "find_all_books", func() { books = findAllBooks(coll) })
+		return c.JSON(http.StatusOK, books)
+	})
+
+	e.GET("/authors", func(c echo.Context) error {
+		var authors []map[string]interface{}
+		timedDBOp(m, "find_authors", func() { authors = findAuthors(coll) })
+		return c.JSON(http.StatusOK, authors)
+	})
+
+	e.GET("/years", func(c echo.Context) error {
+		var years []map[string]interface{}
+		timedDBOp(m, "find_years", func() { years = findYears(coll) })
+		return c.JSON(http.StatusOK, years)
+	})
+
+	e.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(m.Registry(), promhttp.HandlerOpts{})))
+
+	e.GET("/api/books/:id/events", func(c echo.Context) error {
+		bookEvents, err := rec.ForBook(c.Request().Context(), c.Param("id"))
+		if err != nil {
+			return dbErrorResponse(c, err, "DB error")
+		}
+		return c.JSON(http.StatusOK, bookEvents)
+	})
+
+	e.GET("/api/events", func(c echo.Context) error {
+		opts := events.ListOptions{
+			EventType: events.Type(c.QueryParam("type")),
+			Page:      atoiOrDefault(c.QueryParam("page"), 1),
+			Limit:     atoiOrDefault(c.QueryParam("limit"), 20),
+		}
+		if since := c.QueryParam("since"); since != "" {
+			parsed, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				return fmt.Errorf("%w: since must be an RFC3339 timestamp", errs.ErrInvalidBook)
+			}
+			opts.Since = &parsed
+		}
+
+		items, total, err := rec.List(c.Request().Context(), opts)
+		if err != nil {
+			return dbErrorResponse(c, err, "DB error")
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"items": items,
+			"total": total,
+			"page":  opts.Page,
+			"limit": opts.Limit,
+		})
+	})
+
+	e.GET("/api/books/search", func(c echo.Context) error {
+		opts := SearchOptions{
+			Query:  c.QueryParam("q"),
+			Author: c.QueryParam("author"),
+			Year:   c.QueryParam("year"),
+			Page:   atoiOrDefault(c.QueryParam("page"), 1),
+			Limit:  atoiOrDefault(c.QueryParam("limit"), 20),
+			Sort:   c.QueryParam("sort"),
+		}
+
+		var items []map[string]interface{}
+		var total int64
+		var err error
+		timedDBOp(m, "search_books", func() { items, total, err = searchBooks(coll, opts) })
+		if err != nil {
+			return dbErrorResponse(c, err, "DB error")
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"items": items,
+			"total": total,
+			"page":  opts.Page,
+			"limit": opts.Limit,
+		})
+	})
+
+	e.POST("/api/books", func(c echo.Context) error {
+		var book BookStore
+		if err := c.Bind(&book); err != nil {
+			return fmt.Errorf("%w: invalid request body", errs.ErrInvalidBook)
+		}
+
+		if book.ID == "" || book.BookName == "" {
+			return fmt.Errorf("%w: missing required fields: id and title", errs.ErrInvalidBook)
+		}
+
+		filter := bson.M{
+			"ID":          book.ID,
+			"BookName":    book.BookName,
+			"BookAuthor":  book.BookAuthor,
+			"BookEdition": book.BookEdition,
+			"BookPages":   book.BookPages,
+			"BookYear":    book.BookYear,
+		}
+
+		var count int64
+		var err error
+		timedDBOp(m, "count_books", func() { count, err = coll.CountDocuments(context.TODO(), filter) })
+		if err != nil {
+			return dbErrorResponse(c, err, "DB error")
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: a book with these fields already exists", errs.ErrDuplicateBook)
+		}
+
+		timedDBOp(m, "insert_book", func() { _, err = coll.InsertOne(context.TODO(), book) })
+		if err != nil {
+			return dbErrorResponse(c, err, "Insert error")
+		}
+
+		go recordEventAsync(rec, book.ID, events.Created, book, time.Now())
+
+		if book.needsEnrichment() {
+			go enrichBookAsync(coll, book.ID, rec, providers)
+		}
+
+		return c.NoContent(http.StatusCreated)
+	})
+
+	e.PUT("/api/books/:id", func(c echo.Context) error {
+		id := c.Param("id")
+		var book BookStore
+		if err := c.Bind(&book); err != nil {
+			return fmt.Errorf("%w: invalid request body", errs.ErrInvalidBook)
+		}
+
+		filter := bson.M{"ID": id}
+		update := bson.M{"$set": bson.M{
+			"BookName":    book.BookName,
+			"BookAuthor":  book.BookAuthor,
+			"BookEdition": book.BookEdition,
+			"BookPages":   book.BookPages,
+			"BookYear":    book.BookYear,
+		}}
+
+		var res *mongo.UpdateResult
+		var err error
+		timedDBOp(m, "update_book", func() { res, err = coll.UpdateOne(context.TODO(), filter, update) })
+		if err != nil {
+			return dbErrorResponse(c, err, "Update error")
+		}
+		if res.MatchedCount == 0 {
+			return errs.ErrBookNotFound
+		}
+
+		go recordEventAsync(rec, id, events.Updated, book, time.Now())
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.DELETE("/api/books/:id", func(c echo.Context) error {
+		id := c.Param("id")
+		var res *mongo.DeleteResult
+		var err error
+		timedDBOp(m, "delete_book", func() { res, err = coll.DeleteOne(context.TODO(), bson.M{"ID": id}) })
+		if err != nil {
+			return dbErrorResponse(c, err, "Delete error")
+		}
+		if res.DeletedCount == 0 {
+			return errs.ErrBookNotFound
+		}
+
+		go recordEventAsync(rec, id, events.Deleted, nil, time.Now())
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.POST("/api/books/:id/enrich", func(c echo.Context) error {
+		id := c.Param("id")
+
+		var book BookStore
+		var err error
+		timedDBOp(m, "find_book", func() { err = coll.FindOne(context.TODO(), bson.M{"ID": id}).Decode(&book) })
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return errs.ErrBookNotFound
+			}
+			return dbErrorResponse(c, err, "DB error")
+		}
+
+		fields, err := enrich.LookupWith(c.Request().Context(), providers, enrichQuery(book))
+		if err != nil {
+			return writeJSONError(c, http.StatusBadGateway, "Enrichment failed: "+err.Error())
+		}
+
+		update := mergeEnrichedFields(&book, fields)
+		if len(update) == 0 {
+			return c.JSON(http.StatusOK, bookToMap(book))
+		}
+
+		timedDBOp(m, "update_book", func() {
+			_, err = coll.UpdateOne(context.TODO(), bson.M{"ID": id}, bson.M{"$set": update})
+		})
+		if err != nil {
+			return dbErrorResponse(c, err, "Update error")
+		}
+
+		go recordEventAsync(rec, id, events.Enriched, update, time.Now())
+
+		return c.JSON(http.StatusOK, bookToMap(book))
+	})
+}
+
+// enrichQuery builds the provider lookup query from what the book already
+// knows about itself.
+func enrichQuery(book BookStore) enrich.Query {
+	return enrich.Query{
+		Title:  book.BookName,
+		Author: book.BookAuthor,
+		ISBN:   book.ISBN,
+	}
+}
+
+// mergeEnrichedFields copies any fields BookStore is still missing from the
+// looked-up metadata into book, returning a bson set document for the ones
+// actually changed.
+func mergeEnrichedFields(book *BookStore, fields enrich.Fields) bson.M {
+	update := bson.M{}
+
+	if book.BookAuthor == "" && fields.Author != "" {
+		book.BookAuthor = fields.Author
+		update["BookAuthor"] = fields.Author
+	}
+	if book.BookPages == "" && fields.PageCount != "" {
+		book.BookPages = fields.PageCount
+		update["BookPages"] = fields.PageCount
+	}
+	if book.BookYear == "" && fields.Year != "" {
+		book.BookYear = fields.Year
+		update["BookYear"] = fields.Year
+	}
+	if book.ISBN == "" && fields.ISBN != "" {
+		book.ISBN = fields.ISBN
+		update["ISBN"] = fields.ISBN
+	}
+	if book.CoverURL == "" && fields.CoverURL != "" {
+		book.CoverURL = fields.CoverURL
+		update["CoverURL"] = fields.CoverURL
+	}
+	if book.Description == "" && fields.Description != "" {
+		book.Description = fields.Description
+		update["Description"] = fields.Description
+	}
+
+	return update
+}
+
+// enrichBookAsync looks up and merges metadata for a newly inserted book in
+// the background, so POST /api/books does not block on external providers.
+func enrichBookAsync(coll MongoCollection, id string, rec *events.Recorder, providers []enrich.MetadataProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var book BookStore
+	if err := coll.FindOne(ctx, bson.M{"ID": id}).Decode(&book); err != nil {
+		log.Printf("enrichBookAsync: failed to load book %s: %v", id, err)
+		return
+	}
+
+	fields, err := enrich.LookupWith(ctx, providers, enrichQuery(book))
+	if err != nil {
+		log.Printf("enrichBookAsync: lookup failed for book %s: %v", id, err)
+		return
+	}
+
+	update := mergeEnrichedFields(&book, fields)
+	if len(update) == 0 {
+		return
+	}
+
+	if _, err := coll.UpdateOne(ctx, bson.M{"ID": id}, bson.M{"$set": update}); err != nil {
+		log.Printf("enrichBookAsync: failed to save enrichment for book %s: %v", id, err)
+		return
+	}
+
+	recordEventAsync(rec, id, events.Enriched, update, time.Now())
+}
+
+func main() {
+	mongoURI := os.Getenv("MONGO_URI")
+	if mongoURI == "" {
+		mongoURI = "mongodb://localhost:27017"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		log.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	coll, err := prepareDatabase(client, "exercise-1", "information")
+	if err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+	prepareData(client, coll)
+
+	eventsColl := client.Database("exercise-1").Collection("book_events")
+	rec := events.NewRecorder(eventsColl)
+
+	// Trip after 5 consecutive Mongo failures, stay open for 30s, then let up
+	// to 3 half-open trial calls through; the first trial to succeed closes
+	// the breaker again and a failed one reopens it.
+	cb := circuitbreaker.New(5, 30*time.Second, 3)
+	cbColl := NewCBCollection(coll, cb)
+
+	m := metrics.New()
+
+	e := echo.New()
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+	e.Use(metrics.EchoMiddleware(m))
+	e.HTTPErrorHandler = httpErrorHandler
+	e.Renderer = loadTemplates()
+	e.Static("/static", "static")
+
+	registerRoutes(e, cbColl, m, rec, enrich.DefaultProviders())
+
+	e.Logger.Fatal(e.Start(":8080"))
+}
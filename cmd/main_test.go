@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,8 +16,23 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Lilsofie/cc-ss24-exercise-1/enrich"
+	"github.com/Lilsofie/cc-ss24-exercise-1/events"
+	"github.com/Lilsofie/cc-ss24-exercise-1/metrics"
 )
 
+// noopProvider is a MetadataProvider that never matches, so tests exercise
+// the enrichment code paths without making real network calls to Google
+// Books or Open Library.
+type noopProvider struct{}
+
+func (noopProvider) Name() string { return "noop" }
+
+func (noopProvider) Lookup(ctx context.Context, query enrich.Query) (enrich.Fields, error) {
+	return enrich.Fields{}, enrich.ErrNotFound
+}
+
 var (
 	e       *echo.Echo
 	coll    *mongo.Collection
@@ -42,89 +58,32 @@ func setup() {
 
 	prepareData(client, coll)
 
-	e = echo.New()
-	e.Renderer = loadTemplates()
-
-	e.GET("/api/books", func(c echo.Context) error {
-		books := findAllBooks(coll)
-		return c.JSON(http.StatusOK, books)
-	})
-
-	e.POST("/api/books", func(c echo.Context) error {
-		var book BookStore
-		if err := c.Bind(&book); err != nil {
-			return c.String(http.StatusBadRequest, "Invalid request")
-		}
+	eventsColl := client.Database("exercise-1").Collection("book_events")
+	rec := events.NewRecorder(eventsColl)
 
-		if book.ID == "" || book.BookName == "" {
-			return c.String(http.StatusBadRequest, "Missing required fields: id and title")
-		}
+	met := metrics.New()
 
-		filter := bson.M{
-			"ID":          book.ID,
-			"BookName":    book.BookName,
-			"BookAuthor":  book.BookAuthor,
-			"BookEdition": book.BookEdition,
-			"BookPages":   book.BookPages,
-			"BookYear":    book.BookYear,
-		}
-
-		count, err := coll.CountDocuments(context.TODO(), filter)
-		if err != nil {
-			return c.String(http.StatusInternalServerError, "DB error")
-		}
-		if count > 0 {
-			return c.String(http.StatusConflict, "Duplicate entry")
-		}
-
-		_, err = coll.InsertOne(context.TODO(), book)
-		if err != nil {
-			return c.String(http.StatusInternalServerError, "Insert error")
-		}
-
-		return c.NoContent(http.StatusCreated)
-
-	})
-
-	e.PUT("/api/books/:id", func(c echo.Context) error {
-		id := c.Param("id")
-		var book BookStore
-		if err := c.Bind(&book); err != nil {
-			return c.String(http.StatusBadRequest, "Invalid request")
-		}
-
-		filter := bson.M{"ID": id}
-		update := bson.M{"$set": bson.M{
-			"BookName":    book.BookName,
-			"BookAuthor":  book.BookAuthor,
-			"BookEdition": book.BookEdition,
-			"BookPages":   book.BookPages,
-			"BookYear":    book.BookYear,
-		}}
-
-		res, err := coll.UpdateOne(context.TODO(), filter, update)
-		if err != nil {
-			return c.String(http.StatusInternalServerError, "Update error")
-		}
-		if res.MatchedCount == 0 {
-			return c.String(http.StatusNotFound, "Book not found")
-		}
-
-		return c.NoContent(http.StatusOK)
-	})
+	e = echo.New()
+	e.Use(metrics.EchoMiddleware(met))
+	e.HTTPErrorHandler = httpErrorHandler
+	e.Renderer = loadTemplates()
+	registerRoutes(e, coll, met, rec, []enrich.MetadataProvider{noopProvider{}})
+}
 
-	e.DELETE("/api/books/:id", func(c echo.Context) error {
-		id := c.Param("id")
-		res, err := coll.DeleteOne(context.TODO(), bson.M{"ID": id})
-		if err != nil {
-			return c.String(http.StatusInternalServerError, "Delete error")
-		}
-		if res.DeletedCount == 0 {
-			return c.String(http.StatusNotFound, "Book not found")
-		}
+// errorEnvelope decodes the {"status":"error","reason":"..."} body written
+// by writeJSONError.
+type errorEnvelope struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
 
-		return c.NoContent(http.StatusOK)
-	})
+func decodeErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder) errorEnvelope {
+	t.Helper()
+	var env errorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("error response is not a JSON envelope: %v (body: %s)", err, rec.Body.String())
+	}
+	return env
 }
 
 func TestBookAPI(t *testing.T) {
@@ -241,11 +200,6 @@ func TestBookAPI(t *testing.T) {
 func TestAuthorsEndpoint(t *testing.T) {
 	setup()
 
-	e.GET("/authors", func(c echo.Context) error {
-		authors := findAuthors(coll)
-		return c.JSON(http.StatusOK, authors)
-	})
-
 	testBooks := []BookStore{
 		{ID: "a1", BookName: "Book1", BookAuthor: "author1", BookEdition: "1st", BookPages: "111", BookYear: "2020"},
 		{ID: "a2", BookName: "Book2", BookAuthor: "author1", BookEdition: "2nd", BookPages: "222", BookYear: "2021"},
@@ -305,11 +259,6 @@ func TestAuthorsEndpoint(t *testing.T) {
 func TestYearsEndpoint(t *testing.T) {
 	setup()
 
-	e.GET("/years", func(c echo.Context) error {
-		years := findYears(coll)
-		return c.JSON(http.StatusOK, years)
-	})
-
 	testBooks := []BookStore{
 		{ID: "y1", BookName: "Book1", BookAuthor: "author1", BookEdition: "1st", BookPages: "111", BookYear: "2020"},
 		{ID: "y2", BookName: "Book2", BookAuthor: "author2", BookEdition: "2nd", BookPages: "222", BookYear: "2020"},
@@ -384,3 +333,268 @@ func TestYearsEndpoint(t *testing.T) {
 		t.Logf("Failed to clean up test books: %v", err)
 	}
 }
+
+func TestBookAPIErrorEnvelopes(t *testing.T) {
+	setup()
+
+	book := map[string]string{
+		"ID":          "err1",
+		"BookName":    "Error Book",
+		"BookAuthor":  "Error Author",
+		"BookEdition": "1st Edition",
+		"BookPages":   "100",
+		"BookYear":    "2000",
+	}
+	body, _ := json.Marshal(book)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/books expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("duplicate POST /api/books expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+	env := decodeErrorEnvelope(t, rec)
+	if env.Status != "error" || env.Reason == "" {
+		t.Errorf("expected a populated error envelope, got %+v", env)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/books/does-not-exist", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("PUT on missing book expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	env = decodeErrorEnvelope(t, rec)
+	if env.Status != "error" || env.Reason == "" {
+		t.Errorf("expected a populated error envelope, got %+v", env)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/books/does-not-exist", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE on missing book expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	env = decodeErrorEnvelope(t, rec)
+	if env.Status != "error" || env.Reason == "" {
+		t.Errorf("expected a populated error envelope, got %+v", env)
+	}
+
+	_, err := coll.DeleteMany(context.TODO(), bson.M{"ID": "err1"})
+	if err != nil {
+		t.Logf("Failed to clean up test books: %v", err)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	setup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/books expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/books/does-not-exist/enrich", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	out := rec.Body.String()
+	if !strings.Contains(out, "book_api_requests_total") {
+		t.Errorf("expected book_api_requests_total in /metrics output, got: %s", out)
+	}
+	if !strings.Contains(out, "book_api_request_duration_seconds") {
+		t.Errorf("expected book_api_request_duration_seconds in /metrics output, got: %s", out)
+	}
+	if !strings.Contains(out, "book_api_db_operation_duration_seconds") {
+		t.Errorf("expected book_api_db_operation_duration_seconds in /metrics output, got: %s", out)
+	}
+	if !strings.Contains(out, `path="/api/books"`) {
+		t.Errorf("expected a label for /api/books in /metrics output, got: %s", out)
+	}
+}
+
+func TestBookEventsTimeline(t *testing.T) {
+	setup()
+
+	newBook := map[string]string{
+		"ID":          "evt1",
+		"BookName":    "Event Book",
+		"BookAuthor":  "Event Author",
+		"BookEdition": "1st Edition",
+		"BookPages":   "100",
+		"BookYear":    "2000",
+	}
+	body, _ := json.Marshal(newBook)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/books expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+
+	update := map[string]string{"BookName": "Updated Event Book", "BookAuthor": "Event Author"}
+	body, _ = json.Marshal(update)
+	req = httptest.NewRequest(http.MethodPut, "/api/books/evt1", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT /api/books/evt1 expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/books/evt1", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE /api/books/evt1 expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var timeline []events.BookEvent
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		req = httptest.NewRequest(http.MethodGet, "/api/books/evt1/events", nil)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/books/evt1/events expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &timeline); err != nil {
+			t.Fatalf("error unmarshaling events response: %v", err)
+		}
+		if len(timeline) >= 3 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(timeline) != 3 {
+		t.Fatalf("expected 3 events in the timeline, got %d: %+v", len(timeline), timeline)
+	}
+
+	wantOrder := []events.Type{events.Created, events.Updated, events.Deleted}
+	for i, want := range wantOrder {
+		if timeline[i].EventType != want {
+			t.Errorf("event %d: expected type %q, got %q", i, want, timeline[i].EventType)
+		}
+	}
+
+	_, err := coll.DeleteMany(context.TODO(), bson.M{"ID": "evt1"})
+	if err != nil {
+		t.Logf("Failed to clean up test books: %v", err)
+	}
+}
+
+func TestSearchBooksPaginationAndSort(t *testing.T) {
+	setup()
+
+	const numBooks = 50
+	for i := 0; i < numBooks; i++ {
+		book := map[string]string{
+			"ID":          fmt.Sprintf("search%02d", i),
+			"BookName":    fmt.Sprintf("Search Book %02d", i),
+			"BookAuthor":  "Search Author",
+			"BookEdition": "1st Edition",
+			"BookPages":   "100",
+			"BookYear":    fmt.Sprintf("%d", 1970+i),
+		}
+		body, _ := json.Marshal(book)
+		req := httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("POST /api/books expected status %d, got %d", http.StatusCreated, rec.Code)
+		}
+	}
+
+	type searchResponse struct {
+		Items []map[string]interface{} `json:"items"`
+		Total int64                    `json:"total"`
+		Page  int                      `json:"page"`
+		Limit int                      `json:"limit"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books/search?author=Search+Author&sort=year&page=1&limit=20", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/books/search expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var page1 searchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &page1); err != nil {
+		t.Fatalf("error unmarshaling search response: %v", err)
+	}
+	if page1.Total != numBooks {
+		t.Fatalf("expected total %d, got %d", numBooks, page1.Total)
+	}
+	if len(page1.Items) != 20 {
+		t.Fatalf("expected 20 items on page 1, got %d", len(page1.Items))
+	}
+	if page1.Items[0]["year"] != "1970" {
+		t.Errorf("expected first item year 1970, got %v", page1.Items[0]["year"])
+	}
+	if page1.Items[19]["year"] != "1989" {
+		t.Errorf("expected last item on page 1 to be year 1989, got %v", page1.Items[19]["year"])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/books/search?author=Search+Author&sort=year&page=3&limit=20", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/books/search expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var page3 searchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &page3); err != nil {
+		t.Fatalf("error unmarshaling search response: %v", err)
+	}
+	if len(page3.Items) != 10 {
+		t.Fatalf("expected 10 items on the final page, got %d", len(page3.Items))
+	}
+	if page3.Items[0]["year"] != "2010" {
+		t.Errorf("expected first item on page 3 to be year 2010, got %v", page3.Items[0]["year"])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/books/search?author=Search+Author&sort=-year&page=1&limit=20", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/books/search expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var descPage1 searchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &descPage1); err != nil {
+		t.Fatalf("error unmarshaling search response: %v", err)
+	}
+	if descPage1.Items[0]["year"] != "2019" {
+		t.Errorf("expected first item in descending order to be year 2019, got %v", descPage1.Items[0]["year"])
+	}
+
+	_, err := coll.DeleteMany(context.TODO(), bson.M{"BookAuthor": "Search Author"})
+	if err != nil {
+		t.Logf("Failed to clean up test books: %v", err)
+	}
+}
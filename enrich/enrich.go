@@ -0,0 +1,116 @@
+// Package enrich looks up missing book metadata (author, page count, year,
+// ISBN, cover URL, description) from external sources such as Google Books
+// and Open Library.
+package enrich
+
+import (
+	"context"
+	"errors"
+)
+
+// Query describes what we already know about a book, used to search a
+// provider for the rest.
+type Query struct {
+	Title  string
+	Author string
+	ISBN   string
+}
+
+// Fields holds whatever metadata a provider was able to find. Any field left
+// at its zero value was not found and should not overwrite existing data.
+type Fields struct {
+	Author      string
+	PageCount   string
+	Year        string
+	ISBN        string
+	CoverURL    string
+	Description string
+}
+
+// ErrNotFound is returned by a MetadataProvider when it has no match for the
+// given query.
+var ErrNotFound = errors.New("enrich: no metadata found")
+
+// MetadataProvider looks up book metadata from a single external source.
+type MetadataProvider interface {
+	// Name identifies the provider, e.g. for logging.
+	Name() string
+	// Lookup returns the metadata it can find for query, or ErrNotFound if
+	// nothing matched.
+	Lookup(ctx context.Context, query Query) (Fields, error)
+}
+
+// defaultProviders is the ordered list of providers Lookup consults. Earlier
+// providers take precedence: a field already found is not overwritten by a
+// later provider.
+var defaultProviders = []MetadataProvider{
+	NewGoogleBooksProvider(""),
+	NewOpenLibraryProvider(),
+}
+
+// Lookup queries every registered provider in turn and merges the fields
+// they find, stopping once every field has been filled in. A provider error
+// other than ErrNotFound also stops the lookup and is returned.
+func Lookup(ctx context.Context, query Query) (Fields, error) {
+	return LookupWith(ctx, defaultProviders, query)
+}
+
+// DefaultProviders returns the ordered provider list Lookup consults, so
+// callers that need to pass providers explicitly (e.g. registerRoutes) can
+// still default to the real Google Books / Open Library lookups.
+func DefaultProviders() []MetadataProvider {
+	return defaultProviders
+}
+
+// LookupWith is Lookup against an explicit provider list, so callers (and
+// tests) can swap in fakes without touching the package-level defaults.
+func LookupWith(ctx context.Context, providers []MetadataProvider, query Query) (Fields, error) {
+	var merged Fields
+	found := false
+
+	for _, p := range providers {
+		fields, err := p.Lookup(ctx, query)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return merged, err
+		}
+		found = true
+		mergeFields(&merged, fields)
+		if complete(merged) {
+			break
+		}
+	}
+
+	if !found {
+		return merged, ErrNotFound
+	}
+	return merged, nil
+}
+
+func mergeFields(dst *Fields, src Fields) {
+	if dst.Author == "" {
+		dst.Author = src.Author
+	}
+	if dst.PageCount == "" {
+		dst.PageCount = src.PageCount
+	}
+	if dst.Year == "" {
+		dst.Year = src.Year
+	}
+	if dst.ISBN == "" {
+		dst.ISBN = src.ISBN
+	}
+	if dst.CoverURL == "" {
+		dst.CoverURL = src.CoverURL
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+}
+
+func complete(f Fields) bool {
+	return f.Author != "" && f.PageCount != "" && f.Year != "" &&
+		f.ISBN != "" && f.CoverURL != "" && f.Description != ""
+}
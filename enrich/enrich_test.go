@@ -0,0 +1,93 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGoogleBooksProviderLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"items": [{
+				"volumeInfo": {
+					"authors": ["George Orwell"],
+					"pageCount": 328,
+					"publishedDate": "1949-06-08",
+					"description": "A dystopian classic.",
+					"imageLinks": {"thumbnail": "http://example.com/cover.jpg"},
+					"industryIdentifiers": [{"type": "ISBN_13", "identifier": "9780451524935"}]
+				}
+			}]
+		}`))
+	}))
+	defer srv.Close()
+
+	provider := NewGoogleBooksProvider(srv.URL)
+	fields, err := provider.Lookup(context.Background(), Query{Title: "1984"})
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+
+	if fields.Author != "George Orwell" {
+		t.Errorf("expected author %q, got %q", "George Orwell", fields.Author)
+	}
+	if fields.PageCount != "328" {
+		t.Errorf("expected page count %q, got %q", "328", fields.PageCount)
+	}
+	if fields.Year != "1949" {
+		t.Errorf("expected year %q, got %q", "1949", fields.Year)
+	}
+	if fields.ISBN != "9780451524935" {
+		t.Errorf("expected ISBN %q, got %q", "9780451524935", fields.ISBN)
+	}
+	if fields.CoverURL != "http://example.com/cover.jpg" {
+		t.Errorf("expected cover URL %q, got %q", "http://example.com/cover.jpg", fields.CoverURL)
+	}
+}
+
+func TestGoogleBooksProviderNoResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": []}`))
+	}))
+	defer srv.Close()
+
+	provider := NewGoogleBooksProvider(srv.URL)
+	_, err := provider.Lookup(context.Background(), Query{Title: "Nonexistent Book"})
+	if err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLookupWithMergesAcrossProviders(t *testing.T) {
+	googleSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"volumeInfo": {"authors": ["George Orwell"]}}]}`))
+	}))
+	defer googleSrv.Close()
+
+	openLibrarySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"docs": [{"number_of_pages_median": 328}]}`))
+	}))
+	defer openLibrarySrv.Close()
+
+	providers := []MetadataProvider{
+		NewGoogleBooksProvider(googleSrv.URL),
+		NewOpenLibraryProviderWithBaseURL(openLibrarySrv.URL),
+	}
+
+	fields, err := LookupWith(context.Background(), providers, Query{Title: "1984"})
+	if err != nil {
+		t.Fatalf("LookupWith returned error: %v", err)
+	}
+	if fields.Author != "George Orwell" {
+		t.Errorf("expected author from google books, got %q", fields.Author)
+	}
+	if fields.PageCount != "328" {
+		t.Errorf("expected page count from open library, got %q", fields.PageCount)
+	}
+}
@@ -0,0 +1,115 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// googleBooksAPI is the default Google Books volumes search endpoint. Tests
+// override it via NewGoogleBooksProvider's baseURL argument.
+const googleBooksAPI = "https://www.googleapis.com/books/v1/volumes"
+
+// GoogleBooksProvider looks up metadata via the Google Books API.
+type GoogleBooksProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewGoogleBooksProvider builds a GoogleBooksProvider. Passing an empty
+// baseURL uses the real Google Books API; tests pass an httptest.Server URL.
+func NewGoogleBooksProvider(baseURL string) *GoogleBooksProvider {
+	if baseURL == "" {
+		baseURL = googleBooksAPI
+	}
+	return &GoogleBooksProvider{baseURL: baseURL, client: http.DefaultClient}
+}
+
+func (p *GoogleBooksProvider) Name() string { return "google_books" }
+
+type googleBooksResponse struct {
+	Items []struct {
+		VolumeInfo struct {
+			Authors       []string `json:"authors"`
+			PageCount     int      `json:"pageCount"`
+			PublishedDate string   `json:"publishedDate"`
+			Description   string   `json:"description"`
+			ImageLinks    struct {
+				Thumbnail string `json:"thumbnail"`
+			} `json:"imageLinks"`
+			IndustryIdentifiers []struct {
+				Type       string `json:"type"`
+				Identifier string `json:"identifier"`
+			} `json:"industryIdentifiers"`
+		} `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+func (p *GoogleBooksProvider) Lookup(ctx context.Context, query Query) (Fields, error) {
+	q := searchTerms(query)
+	if q == "" {
+		return Fields{}, ErrNotFound
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?q="+url.QueryEscape(q), nil)
+	if err != nil {
+		return Fields{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Fields{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Fields{}, fmt.Errorf("enrich: google books returned %d", resp.StatusCode)
+	}
+
+	var parsed googleBooksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return Fields{}, err
+	}
+	if len(parsed.Items) == 0 {
+		return Fields{}, ErrNotFound
+	}
+
+	info := parsed.Items[0].VolumeInfo
+	fields := Fields{
+		Description: info.Description,
+		CoverURL:    info.ImageLinks.Thumbnail,
+	}
+	if len(info.Authors) > 0 {
+		fields.Author = info.Authors[0]
+	}
+	if info.PageCount > 0 {
+		fields.PageCount = strconv.Itoa(info.PageCount)
+	}
+	if len(info.PublishedDate) >= 4 {
+		fields.Year = info.PublishedDate[:4]
+	}
+	for _, id := range info.IndustryIdentifiers {
+		if id.Type == "ISBN_13" || id.Type == "ISBN_10" {
+			fields.ISBN = id.Identifier
+			break
+		}
+	}
+
+	return fields, nil
+}
+
+func searchTerms(query Query) string {
+	switch {
+	case query.ISBN != "":
+		return "isbn:" + query.ISBN
+	case query.Title != "" && query.Author != "":
+		return query.Title + " " + query.Author
+	case query.Title != "":
+		return query.Title
+	default:
+		return ""
+	}
+}
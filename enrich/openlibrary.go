@@ -0,0 +1,96 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// openLibraryAPI is the default Open Library search endpoint.
+const openLibraryAPI = "https://openlibrary.org/search.json"
+
+// OpenLibraryProvider looks up metadata via the Open Library search API.
+type OpenLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenLibraryProvider builds an OpenLibraryProvider against the real
+// Open Library API.
+func NewOpenLibraryProvider() *OpenLibraryProvider {
+	return &OpenLibraryProvider{baseURL: openLibraryAPI, client: http.DefaultClient}
+}
+
+// NewOpenLibraryProviderWithBaseURL builds an OpenLibraryProvider against a
+// custom base URL, used by tests to point at an httptest.Server.
+func NewOpenLibraryProviderWithBaseURL(baseURL string) *OpenLibraryProvider {
+	return &OpenLibraryProvider{baseURL: baseURL, client: http.DefaultClient}
+}
+
+func (p *OpenLibraryProvider) Name() string { return "open_library" }
+
+type openLibraryResponse struct {
+	Docs []struct {
+		AuthorName    []string `json:"author_name"`
+		FirstPublish  int      `json:"first_publish_year"`
+		NumberOfPages int      `json:"number_of_pages_median"`
+		ISBN          []string `json:"isbn"`
+		CoverID       int      `json:"cover_i"`
+		FirstSentence []string `json:"first_sentence"`
+	} `json:"docs"`
+}
+
+func (p *OpenLibraryProvider) Lookup(ctx context.Context, query Query) (Fields, error) {
+	if query.Title == "" {
+		return Fields{}, ErrNotFound
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?q="+url.QueryEscape(query.Title), nil)
+	if err != nil {
+		return Fields{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Fields{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Fields{}, fmt.Errorf("enrich: open library returned %d", resp.StatusCode)
+	}
+
+	var parsed openLibraryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return Fields{}, err
+	}
+	if len(parsed.Docs) == 0 {
+		return Fields{}, ErrNotFound
+	}
+
+	doc := parsed.Docs[0]
+	fields := Fields{}
+	if len(doc.AuthorName) > 0 {
+		fields.Author = doc.AuthorName[0]
+	}
+	if doc.FirstPublish > 0 {
+		fields.Year = strconv.Itoa(doc.FirstPublish)
+	}
+	if doc.NumberOfPages > 0 {
+		fields.PageCount = strconv.Itoa(doc.NumberOfPages)
+	}
+	if len(doc.ISBN) > 0 {
+		fields.ISBN = doc.ISBN[0]
+	}
+	if doc.CoverID > 0 {
+		fields.CoverURL = fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-M.jpg", doc.CoverID)
+	}
+	if len(doc.FirstSentence) > 0 {
+		fields.Description = doc.FirstSentence[0]
+	}
+
+	return fields, nil
+}
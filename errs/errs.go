@@ -0,0 +1,35 @@
+// Package errs holds the sentinel errors handlers return for expected
+// failure modes, plus the mapping from those errors to HTTP status codes.
+package errs
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrBookNotFound is returned when a lookup, update, or delete targets
+	// an ID that does not exist in the collection.
+	ErrBookNotFound = errors.New("book not found")
+	// ErrDuplicateBook is returned when an insert matches a book that
+	// already exists in every field.
+	ErrDuplicateBook = errors.New("duplicate book")
+	// ErrInvalidBook is returned when a request body fails to bind or is
+	// missing required fields.
+	ErrInvalidBook = errors.New("invalid book")
+)
+
+// StatusCode maps a sentinel error (or one wrapping it) to the HTTP status
+// code a handler should respond with. Unrecognized errors map to 500.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrBookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDuplicateBook):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidBook):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,146 @@
+// Package events records an append-only audit log of book mutations
+// (create, update, delete, enrich) to a dedicated MongoDB collection, and
+// lets callers query it back as a chronological timeline.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Type identifies what happened to a book.
+type Type string
+
+const (
+	Created  Type = "created"
+	Updated  Type = "updated"
+	Deleted  Type = "deleted"
+	Enriched Type = "enriched"
+)
+
+// BookEvent is one entry in the book_events collection.
+type BookEvent struct {
+	ID        string    `json:"ID" bson:"ID"`
+	BookID    string    `json:"BookID" bson:"BookID"`
+	EventType Type      `json:"EventType" bson:"EventType"`
+	Timestamp time.Time `json:"Timestamp" bson:"Timestamp"`
+	Payload   string    `json:"Payload" bson:"Payload"`
+}
+
+// Collection is the subset of *mongo.Collection the Recorder needs.
+type Collection interface {
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+}
+
+// Recorder writes book events to, and reads them back from, a Collection.
+type Recorder struct {
+	coll Collection
+}
+
+// NewRecorder builds a Recorder backed by coll.
+func NewRecorder(coll Collection) *Recorder {
+	return &Recorder{coll: coll}
+}
+
+// Record appends one event for bookID, marshaling payload (e.g. the book
+// itself, or just the changed fields) into the event's Payload, timestamped
+// with time.Now().
+func (r *Recorder) Record(ctx context.Context, bookID string, eventType Type, payload interface{}) error {
+	return r.RecordAt(ctx, bookID, eventType, payload, time.Now())
+}
+
+// RecordAt is Record with an explicit timestamp. Callers that record events
+// from independent goroutines should capture the timestamp before spawning,
+// so the event order reflects the order things actually happened rather
+// than whichever goroutine the scheduler ran first.
+func (r *Recorder) RecordAt(ctx context.Context, bookID string, eventType Type, payload interface{}, ts time.Time) error {
+	marshaled, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event := BookEvent{
+		ID:        primitive.NewObjectID().Hex(),
+		BookID:    bookID,
+		EventType: eventType,
+		Timestamp: ts,
+		Payload:   string(marshaled),
+	}
+
+	_, err = r.coll.InsertOne(ctx, event)
+	return err
+}
+
+// ForBook returns every event recorded for bookID, oldest first.
+func (r *Recorder) ForBook(ctx context.Context, bookID string) ([]BookEvent, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"BookID": bookID}, options.Find().SetSort(bson.D{{Key: "Timestamp", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []BookEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListOptions filters and paginates List.
+type ListOptions struct {
+	Since     *time.Time
+	EventType Type
+	Page      int
+	Limit     int
+}
+
+// List returns events matching opts, oldest first, along with the total
+// number of matching events (ignoring pagination).
+func (r *Recorder) List(ctx context.Context, opts ListOptions) (items []BookEvent, total int64, err error) {
+	filter := bson.M{}
+	if opts.Since != nil {
+		filter["Timestamp"] = bson.M{"$gte": *opts.Since}
+	}
+	if opts.EventType != "" {
+		filter["EventType"] = opts.EventType
+	}
+
+	total, err = r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page := opts.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := opts.Limit
+	if limit < 1 {
+		limit = 20
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "Timestamp", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.coll.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	items = []BookEvent{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
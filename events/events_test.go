@@ -0,0 +1,202 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fakeCollection is an in-memory stand-in for the book_events collection,
+// enough of InsertOne/Find/CountDocuments to exercise the Recorder without a
+// real MongoDB connection. Find supports exactly the filters the Recorder
+// issues (an exact BookID or EventType match, a Timestamp $gte bound,
+// combined with AND semantics) and always returns results sorted by
+// Timestamp ascending, mirroring the SetSort every Recorder query passes.
+type fakeCollection struct {
+	docs []BookEvent
+}
+
+func (f *fakeCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	f.docs = append(f.docs, document.(BookEvent))
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (f *fakeCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	matched := f.filter(filter)
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Timestamp.Before(matched[j].Timestamp) })
+
+	if len(opts) > 0 {
+		if skip := opts[0].Skip; skip != nil && *skip > 0 {
+			if int(*skip) >= len(matched) {
+				matched = nil
+			} else {
+				matched = matched[*skip:]
+			}
+		}
+		if limit := opts[0].Limit; limit != nil && *limit > 0 && int(*limit) < len(matched) {
+			matched = matched[:*limit]
+		}
+	}
+
+	documents := make([]interface{}, len(matched))
+	for i, doc := range matched {
+		documents[i] = doc
+	}
+	return mongo.NewCursorFromDocuments(documents, nil, nil)
+}
+
+func (f *fakeCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return int64(len(f.filter(filter))), nil
+}
+
+// filter returns the docs matching filter, which is always a bson.M built by
+// the Recorder itself (see ForBook and List).
+func (f *fakeCollection) filter(filter interface{}) []BookEvent {
+	m, _ := filter.(bson.M)
+
+	var matched []BookEvent
+	for _, doc := range f.docs {
+		if bookID, ok := m["BookID"]; ok && doc.BookID != bookID {
+			continue
+		}
+		if eventType, ok := m["EventType"]; ok && doc.EventType != eventType {
+			continue
+		}
+		if ts, ok := m["Timestamp"].(bson.M); ok {
+			if gte, ok := ts["$gte"].(time.Time); ok && doc.Timestamp.Before(gte) {
+				continue
+			}
+		}
+		matched = append(matched, doc)
+	}
+	return matched
+}
+
+func TestRecorderRecordAppendsInInsertOrder(t *testing.T) {
+	fake := &fakeCollection{}
+	r := NewRecorder(fake)
+
+	if err := r.Record(context.Background(), "book1", Created, map[string]string{"title": "1984"}); err != nil {
+		t.Fatalf("Record(created) failed: %v", err)
+	}
+	if err := r.Record(context.Background(), "book1", Updated, map[string]string{"title": "Nineteen Eighty-Four"}); err != nil {
+		t.Fatalf("Record(updated) failed: %v", err)
+	}
+	if err := r.Record(context.Background(), "book1", Deleted, nil); err != nil {
+		t.Fatalf("Record(deleted) failed: %v", err)
+	}
+
+	if len(fake.docs) != 3 {
+		t.Fatalf("expected 3 recorded events, got %d", len(fake.docs))
+	}
+
+	wantOrder := []Type{Created, Updated, Deleted}
+	for i, want := range wantOrder {
+		if fake.docs[i].EventType != want {
+			t.Errorf("event %d: expected type %q, got %q", i, want, fake.docs[i].EventType)
+		}
+		if fake.docs[i].BookID != "book1" {
+			t.Errorf("event %d: expected BookID %q, got %q", i, "book1", fake.docs[i].BookID)
+		}
+		if fake.docs[i].ID == "" {
+			t.Errorf("event %d: expected a generated ID", i)
+		}
+	}
+}
+
+func TestRecorderForBookReturnsChronologicalOrder(t *testing.T) {
+	fake := &fakeCollection{}
+	r := NewRecorder(fake)
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Recorded out of chronological order, the way independent goroutines
+	// racing to record create/update/delete events might land.
+	if err := r.RecordAt(context.Background(), "book1", Updated, nil, base.Add(2*time.Minute)); err != nil {
+		t.Fatalf("RecordAt(updated) failed: %v", err)
+	}
+	if err := r.RecordAt(context.Background(), "book1", Created, nil, base); err != nil {
+		t.Fatalf("RecordAt(created) failed: %v", err)
+	}
+	if err := r.RecordAt(context.Background(), "book2", Created, nil, base); err != nil {
+		t.Fatalf("RecordAt(book2 created) failed: %v", err)
+	}
+	if err := r.RecordAt(context.Background(), "book1", Deleted, nil, base.Add(5*time.Minute)); err != nil {
+		t.Fatalf("RecordAt(deleted) failed: %v", err)
+	}
+
+	got, err := r.ForBook(context.Background(), "book1")
+	if err != nil {
+		t.Fatalf("ForBook failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events for book1, got %d: %+v", len(got), got)
+	}
+
+	wantOrder := []Type{Created, Updated, Deleted}
+	for i, want := range wantOrder {
+		if got[i].EventType != want {
+			t.Errorf("event %d: expected type %q, got %q", i, want, got[i].EventType)
+		}
+	}
+}
+
+func TestRecorderListFiltersAndPaginates(t *testing.T) {
+	fake := &fakeCollection{}
+	r := NewRecorder(fake)
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	seed := []struct {
+		bookID string
+		typ    Type
+		ts     time.Time
+	}{
+		{"book1", Created, base},
+		{"book1", Updated, base.Add(time.Minute)},
+		{"book2", Created, base.Add(2 * time.Minute)},
+		{"book1", Deleted, base.Add(3 * time.Minute)},
+	}
+	for _, e := range seed {
+		if err := r.RecordAt(context.Background(), e.bookID, e.typ, nil, e.ts); err != nil {
+			t.Fatalf("RecordAt failed: %v", err)
+		}
+	}
+
+	items, total, err := r.List(context.Background(), ListOptions{EventType: Created, Page: 1, Limit: 20})
+	if err != nil {
+		t.Fatalf("List(type=created) failed: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 created events, got %d", total)
+	}
+	if len(items) != 2 || items[0].BookID != "book1" || items[1].BookID != "book2" {
+		t.Fatalf("unexpected created events: %+v", items)
+	}
+
+	since := base.Add(90 * time.Second)
+	items, total, err = r.List(context.Background(), ListOptions{Since: &since, Page: 1, Limit: 20})
+	if err != nil {
+		t.Fatalf("List(since) failed: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 events since %v, got %d", since, total)
+	}
+	if len(items) != 2 || items[0].EventType != Created || items[1].EventType != Deleted {
+		t.Fatalf("unexpected events since %v: %+v", since, items)
+	}
+
+	items, total, err = r.List(context.Background(), ListOptions{Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("List(page=2) failed: %v", err)
+	}
+	if total != 4 {
+		t.Fatalf("expected total 4 ignoring pagination, got %d", total)
+	}
+	if len(items) != 2 || items[0].BookID != "book2" || items[1].EventType != Deleted {
+		t.Fatalf("unexpected events on page 2: %+v", items)
+	}
+}
@@ -0,0 +1,73 @@
+// Package metrics instruments the book API with Prometheus counters and
+// histograms for request volume, request latency, DB operation latency,
+// and per-endpoint errors.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is the instrumentation surface the book API records observations
+// through. It is an interface so handlers and middleware can be tested
+// against a fake without standing up a real Prometheus registry.
+type Metrics interface {
+	// ObserveRequest records one completed HTTP request.
+	ObserveRequest(method, path string, status int, duration time.Duration)
+	// ObserveDBOperation records the latency of one MongoDB call.
+	ObserveDBOperation(operation string, duration time.Duration)
+	// Registry exposes the underlying Prometheus registry for /metrics.
+	Registry() *prometheus.Registry
+}
+
+type prometheusMetrics struct {
+	requestCount    *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	dbDuration      *prometheus.HistogramVec
+	errorCount      *prometheus.CounterVec
+	registry        *prometheus.Registry
+}
+
+// New builds a Metrics backed by a fresh Prometheus registry.
+func New() Metrics {
+	m := &prometheusMetrics{
+		requestCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "book_api_requests_total",
+			Help: "Total number of HTTP requests handled by the book API.",
+		}, []string{"method", "path", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "book_api_request_duration_seconds",
+			Help: "Latency of HTTP requests handled by the book API.",
+		}, []string{"method", "path"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "book_api_db_operation_duration_seconds",
+			Help: "Latency of MongoDB operations issued by the book API.",
+		}, []string{"operation"}),
+		errorCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "book_api_errors_total",
+			Help: "Total number of error (4xx/5xx) responses per endpoint.",
+		}, []string{"path"}),
+		registry: prometheus.NewRegistry(),
+	}
+
+	m.registry.MustRegister(m.requestCount, m.requestDuration, m.dbDuration, m.errorCount)
+	return m
+}
+
+func (m *prometheusMetrics) ObserveRequest(method, path string, status int, duration time.Duration) {
+	m.requestCount.WithLabelValues(method, path, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(method, path).Observe(duration.Seconds())
+	if status >= 400 {
+		m.errorCount.WithLabelValues(path).Inc()
+	}
+}
+
+func (m *prometheusMetrics) ObserveDBOperation(operation string, duration time.Duration) {
+	m.dbDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (m *prometheusMetrics) Registry() *prometheus.Registry {
+	return m.registry
+}
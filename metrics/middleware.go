@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Lilsofie/cc-ss24-exercise-1/errs"
+)
+
+// EchoMiddleware records one ObserveRequest call per HTTP request, timing
+// the full handler chain and deriving the eventual response status from
+// the handler's returned error rather than c.Response().Status, which is
+// still unset at this point for errors the central httpErrorHandler hasn't
+// written yet.
+func EchoMiddleware(m Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				} else {
+					status = errs.StatusCode(err)
+				}
+			}
+
+			path := c.Path()
+			if path == "" {
+				path = c.Request().URL.Path
+			}
+			m.ObserveRequest(c.Request().Method, path, status, time.Since(start))
+
+			return err
+		}
+	}
+}